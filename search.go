@@ -2,54 +2,78 @@ package dataframe
 
 import (
 	"context"
+	"fmt"
 	"golang.org/x/sync/errgroup"
+	"regexp"
 	"runtime"
-	"sync"
+	"strings"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
-// Search is used to find particular values in a given Series.
-// It will find all values that are between lower and upper bounds (inclusive).
-// It will return a slice containing the rows which contain values within the bounds.
-// If Search is canceled, an incomplete list of the values "found so far" is returned.
-func Search(ctx context.Context, s Series, lower, upper interface{}, r ...Range) ([]int, error) {
-
-	s.Lock()
-	defer s.Unlock()
+// SearchOptions configures the Range searched and the concurrent
+// partitioning used by Search, SearchBatch, SearchFunc and SearchPattern.
+// It is taken as a trailing variadic argument (like the Range that every
+// other function in this package accepts), so existing callers that don't
+// need any of this can keep omitting it entirely.
+type SearchOptions struct {
+	// Range restricts the search to a sub-range of the Series. The zero
+	// value searches the whole Series, matching the long-standing
+	// `r ...Range` convention used elsewhere in this package.
+	Range Range
+	// NumWorkers caps how many goroutines the search is split across. If
+	// zero (the default), runtime.NumCPU() is used. It is always capped to
+	// the number of rows being searched, so it is safe to leave this unset.
+	NumWorkers int
+	// Sorted forces the per-worker results to be combined with a verified
+	// ascending merge rather than the default in-order concatenation. Since
+	// each worker already owns a disjoint, contiguous, increasing range of
+	// rows, concatenating their (individually ascending) results in worker
+	// order already produces an ascending slice at no extra cost, so most
+	// callers do not need to set this. It exists for callers who would
+	// rather not depend on that partitioning invariant holding.
+	Sorted bool
+}
 
-	if len(r) == 0 {
-		r = append(r, Range{})
+// firstSearchOptions returns opts[0], or the zero value SearchOptions if
+// opts is empty, so that SearchOptions can be taken as an optional trailing
+// argument instead of a mandatory one.
+func firstSearchOptions(opts []SearchOptions) SearchOptions {
+	if len(opts) == 0 {
+		return SearchOptions{}
 	}
+	return opts[0]
+}
 
-	var equalCheck bool
-	if cmp.Equal(lower, upper, cmpopts.IgnoreUnexported()) {
-		equalCheck = true
+// searchPartitions divides [start, end] (inclusive) into contiguous,
+// ascending sub-ranges, one per worker. numWorkers is capped to the number
+// of rows in range so that a small range never hands out empty or duplicate
+// sub-ranges (as `div := (end-start+1)/nCores` going to zero used to cause).
+func searchPartitions(start, end, numWorkers int) []Range {
+	if numWorkers <= 0 {
+		numWorkers = runtime.NumCPU()
 	}
 
-	start, end, err := r[0].Limits(s.NRows(Options{DontLock: true}))
-	if err != nil {
-		return nil, err
+	n := end - start + 1
+	if numWorkers > n {
+		numWorkers = n
 	}
 
-	nCores := runtime.NumCPU()
+	chunk := n / numWorkers
+	if chunk < 1 {
+		chunk = 1
+	}
 
-	// Group search range equally amongst each core
-	div := (end - start + 1) / nCores
+	subRanges := make([]Range, 0, numWorkers)
 
-	subRanges := []Range{}
+	for i := 0; i < numWorkers; i++ {
+		subStart := start + i*chunk
 
-	for i := 0; i < nCores; i++ {
-		var subStart int
 		var subEnd int
-
-		if i != nCores-1 {
-			subStart = i * div
-			subEnd = (i+1)*div - 1
+		if i != numWorkers-1 {
+			subEnd = subStart + chunk - 1
 		} else {
-			// last code
-			subStart = i * div
 			subEnd = end
 		}
 
@@ -59,28 +83,93 @@ func Search(ctx context.Context, s Series, lower, upper interface{}, r ...Range)
 		})
 	}
 
-	// Concurrently search each subRange for values in range
-	var g errgroup.Group
+	return subRanges
+}
 
-	var mapProtect sync.Mutex
-	mapRows := map[int][]int{} // For each core store the rows we have found so far
+// mergeAscending combines per-worker ascending row slices into a single
+// ascending slice. Because searchPartitions hands out disjoint, contiguous,
+// increasing ranges, concatenating the slices in worker order (the default,
+// verify == false) already produces an ascending result with no extra work.
+// When verify is true, a k-way merge is performed instead of relying on that
+// invariant; since the number of workers is small and bounded (NumCPU or
+// SearchOptions.NumWorkers), this is still effectively O(N).
+func mergeAscending(results [][]int, verify bool) []int {
+	var count int
+	for _, r := range results {
+		count += len(r)
+	}
+	rows := make([]int, 0, count)
 
-	for i := 0; i < nCores; i++ {
+	if !verify {
+		for _, r := range results {
+			rows = append(rows, r...)
+		}
+		return rows
+	}
+
+	idx := make([]int, len(results))
+	for len(rows) < count {
+		next := -1
+		for w, r := range results {
+			if idx[w] >= len(r) {
+				continue
+			}
+			if next == -1 || r[idx[w]] < results[next][idx[next]] {
+				next = w
+			}
+		}
+		rows = append(rows, results[next][idx[next]])
+		idx[next]++
+	}
+	return rows
+}
+
+// Search is used to find particular values in a given Series.
+// It will find all values that are between lower and upper bounds (inclusive).
+// It will return a slice containing the rows which contain values within the bounds.
+// If Search is canceled, an incomplete list of the values "found so far" is returned.
+func Search(ctx context.Context, s Series, lower, upper interface{}, searchOpts ...SearchOptions) ([]int, error) {
+
+	opts := firstSearchOptions(searchOpts)
+
+	s.Lock()
+	defer s.Unlock()
+
+	var equalCheck bool
+	if cmp.Equal(lower, upper, cmpopts.IgnoreUnexported()) {
+		equalCheck = true
+	}
+
+	start, end, err := opts.Range.Limits(s.NRows(Options{DontLock: true}))
+	if err != nil {
+		return nil, err
+	}
+
+	subRanges := searchPartitions(start, end, opts.NumWorkers)
+	numWorkers := len(subRanges)
+
+	g, gctx := errgroup.WithContext(ctx)
+
+	results := make([][]int, numWorkers)
+
+	for i := 0; i < numWorkers; i++ {
 		i := i
 		g.Go(func() error {
 
 			rowsFound := []int{} // Store all rows that we have found
 
+			// Always leave results[i] indexable with whatever has been found
+			// so far, even on the early-return path below (context canceled
+			// or a peer errored), so a cancellation doesn't throw away rows
+			// this worker already matched.
 			defer func() {
-				mapProtect.Lock()
-				mapRows[i] = rowsFound
-				mapProtect.Unlock()
+				results[i] = rowsFound
 			}()
 
 			for row := *subRanges[i].Start; row < *subRanges[i].End+1; row++ {
 
 				// Cancel for loop if context is canceled
-				if err := ctx.Err(); err != nil {
+				if err := gctx.Err(); err != nil {
 					return err
 				}
 
@@ -105,19 +194,373 @@ func Search(ctx context.Context, s Series, lower, upper interface{}, r ...Range)
 
 	err = g.Wait()
 
-	// Convert rows found to Range slice
-	var rows []int
-	var count int
-	for i := 0; i < nCores; i++ {
-		count = count + len(mapRows[i])
+	return mergeAscending(results, opts.Sorted), err
+}
+
+// SearchQuery represents a lower/upper bound pair (inclusive) to be searched
+// for by SearchBatch. The same rules that Search applies to lower and upper
+// apply here: if they are equal, only values equal to them are matched.
+type SearchQuery struct {
+	Lower interface{}
+	Upper interface{}
+}
+
+// SearchBatch is used to find particular values in a given Series for many
+// queries at once. Rather than scanning the Series once per query, it performs
+// a single concurrent pass over the Series (reusing the same partitioning as
+// Search) and evaluates every query against each row as it is visited.
+//
+// The returned [][]int is aligned with queries: the i'th element contains the
+// rows that matched queries[i]. If SearchBatch is canceled, an incomplete list
+// of the values "found so far" is returned for each query, matching the
+// partial-result contract of Search.
+func SearchBatch(ctx context.Context, s Series, queries []SearchQuery, searchOpts ...SearchOptions) ([][]int, error) {
+
+	opts := firstSearchOptions(searchOpts)
+
+	s.Lock()
+	defer s.Unlock()
+
+	equalChecks := make([]bool, len(queries))
+	for i, q := range queries {
+		if cmp.Equal(q.Lower, q.Upper, cmpopts.IgnoreUnexported()) {
+			equalChecks[i] = true
+		}
 	}
-	rows = make([]int, 0, count)
 
-	// Store found rows into 1 int
-	for i := 0; i < nCores; i++ {
-		foundRows := mapRows[i]
-		rows = append(rows, foundRows...)
+	start, end, err := opts.Range.Limits(s.NRows(Options{DontLock: true}))
+	if err != nil {
+		return nil, err
+	}
+
+	subRanges := searchPartitions(start, end, opts.NumWorkers)
+	numWorkers := len(subRanges)
+
+	g, gctx := errgroup.WithContext(ctx)
+
+	// results[worker][query] holds the ascending rows that worker found for query.
+	results := make([][][]int, numWorkers)
+
+	for i := 0; i < numWorkers; i++ {
+		i := i
+		g.Go(func() error {
+
+			rowsFound := make([][]int, len(queries)) // Store all rows that we have found, per query
+			for q := range rowsFound {
+				rowsFound[q] = []int{}
+			}
+
+			// Always leave results[i] indexable, even on the early-return
+			// path below (context canceled or a peer errored): the merge
+			// step indexes every worker's per-query slice unconditionally.
+			defer func() {
+				results[i] = rowsFound
+			}()
+
+			for row := *subRanges[i].Start; row < *subRanges[i].End+1; row++ {
+
+				// Cancel for loop if context is canceled
+				if err := gctx.Err(); err != nil {
+					return err
+				}
+
+				val := s.Value(row, Options{DontLock: true})
+
+				for q, query := range queries {
+					// Check if val is in range for this query
+					if equalChecks[q] {
+						if s.IsEqualFunc(val, query.Lower) {
+							rowsFound[q] = append(rowsFound[q], row)
+						}
+					} else {
+						if !s.IsLessThanFunc(val, query.Lower) && (s.IsLessThanFunc(val, query.Upper) || s.IsEqualFunc(val, query.Upper)) {
+							rowsFound[q] = append(rowsFound[q], row)
+						}
+					}
+				}
+
+			}
+
+			return nil
+		})
+	}
+
+	err = g.Wait()
+
+	rows := make([][]int, len(queries))
+	perQuery := make([][]int, numWorkers)
+	for q := range rows {
+		for w := 0; w < numWorkers; w++ {
+			perQuery[w] = results[w][q]
+		}
+		rows[q] = mergeAscending(perQuery, opts.Sorted)
 	}
 
 	return rows, err
 }
+
+// SearchFunc is used to find rows in a given Series using an arbitrary
+// predicate. fn is evaluated once per row (in the given Range) as
+// fn(val, row), where val is the row's value as returned by Series.Value.
+// SearchFunc reuses the same concurrent partitioning as Search and shares its
+// partial-result contract: if SearchFunc is canceled (or fn returns an
+// error), an incomplete list of the rows "found so far" is returned alongside
+// the error.
+func SearchFunc(ctx context.Context, s Series, fn func(val interface{}, row int) (bool, error), searchOpts ...SearchOptions) ([]int, error) {
+
+	opts := firstSearchOptions(searchOpts)
+
+	s.Lock()
+	defer s.Unlock()
+
+	start, end, err := opts.Range.Limits(s.NRows(Options{DontLock: true}))
+	if err != nil {
+		return nil, err
+	}
+
+	subRanges := searchPartitions(start, end, opts.NumWorkers)
+	numWorkers := len(subRanges)
+
+	g, gctx := errgroup.WithContext(ctx)
+
+	results := make([][]int, numWorkers)
+
+	for i := 0; i < numWorkers; i++ {
+		i := i
+		g.Go(func() error {
+
+			rowsFound := []int{} // Store all rows that we have found
+
+			// Always leave results[i] indexable with whatever has been found
+			// so far, even on the early-return path below (context canceled,
+			// a peer errored, or fn itself errored), so a cancellation
+			// doesn't throw away rows this worker already matched.
+			defer func() {
+				results[i] = rowsFound
+			}()
+
+			for row := *subRanges[i].Start; row < *subRanges[i].End+1; row++ {
+
+				// Cancel for loop if context is canceled
+				if err := gctx.Err(); err != nil {
+					return err
+				}
+
+				val := s.Value(row, Options{DontLock: true})
+
+				ok, err := fn(val, row)
+				if err != nil {
+					return err
+				}
+				if ok {
+					rowsFound = append(rowsFound, row)
+				}
+			}
+
+			return nil
+		})
+	}
+
+	err = g.Wait()
+
+	return mergeAscending(results, opts.Sorted), err
+}
+
+// PatternMode selects how SearchPattern interprets its pattern argument.
+type PatternMode int
+
+const (
+	// PatternGlob compiles pattern into a glob matcher supporting '*' (any
+	// run of characters, including none) and '?' (exactly one character).
+	// This is the default mode.
+	PatternGlob PatternMode = iota
+	// PatternRegexp compiles pattern as a Go regular expression (see the
+	// regexp package for syntax).
+	PatternRegexp
+)
+
+// PatternOptions configures how SearchPattern compiles and applies pattern.
+type PatternOptions struct {
+	Mode PatternMode
+	// CaseSensitive controls case folding for PatternGlob. It has no effect
+	// on PatternRegexp; use the "(?i)" flag within the pattern instead.
+	CaseSensitive bool
+}
+
+// patternMatcher is satisfied by both *regexp.Regexp and the matcher compiled
+// for PatternGlob, so SearchPattern can treat both modes uniformly.
+type patternMatcher interface {
+	MatchString(string) bool
+}
+
+// SearchPattern is used to find rows in a SeriesString whose value matches
+// pattern. pattern is compiled once up front according to patternOpts.Mode
+// and an error is returned immediately if it fails to compile, rather than
+// surfacing during row evaluation. SearchPattern is built on top of
+// SearchFunc, so it shares the same concurrent partitioning and
+// partial-result contract on cancellation as Search.
+func SearchPattern(ctx context.Context, s Series, pattern string, patternOpts PatternOptions, searchOpts ...SearchOptions) ([]int, error) {
+
+	if _, ok := s.(*SeriesString); !ok {
+		return nil, fmt.Errorf("dataframe: SearchPattern is only supported for SeriesString")
+	}
+
+	matcher, err := compilePattern(pattern, patternOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return SearchFunc(ctx, s, func(val interface{}, row int) (bool, error) {
+		str, ok := val.(string)
+		if !ok {
+			return false, nil
+		}
+		return matcher.MatchString(str), nil
+	}, searchOpts...)
+}
+
+func compilePattern(pattern string, opts PatternOptions) (patternMatcher, error) {
+	switch opts.Mode {
+	case PatternRegexp:
+		return regexp.Compile(pattern)
+	default:
+		return compileGlob(pattern, opts.CaseSensitive)
+	}
+}
+
+// globMatcher is a compiled glob pattern supporting '*' and '?' wildcards.
+// It is split into literal segments (with '?' left in place as a
+// single-character wildcard) at compile time so that MatchString never needs
+// to reason about zero-length segments: empty segments produced by
+// consecutive or leading/trailing '*' are dropped during compilation instead
+// of being matched against at evaluation time, which is where libraries of
+// this kind tend to panic with an index out of range.
+type globMatcher struct {
+	segments      [][]rune
+	anchoredStart bool
+	anchoredEnd   bool
+	caseSensitive bool
+}
+
+func compileGlob(pattern string, caseSensitive bool) (*globMatcher, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("dataframe: glob pattern must not be empty")
+	}
+
+	if !caseSensitive {
+		pattern = strings.ToLower(pattern)
+	}
+
+	anchoredStart := !strings.HasPrefix(pattern, "*")
+	anchoredEnd := !strings.HasSuffix(pattern, "*")
+
+	var segments [][]rune
+	for _, raw := range strings.Split(pattern, "*") {
+		if raw == "" {
+			// Produced by a leading/trailing or consecutive '*'; it
+			// contributes no literal to match against, so drop it rather
+			// than carrying a zero-length segment into MatchString.
+			continue
+		}
+		segments = append(segments, []rune(raw))
+	}
+
+	return &globMatcher{
+		segments:      segments,
+		anchoredStart: anchoredStart,
+		anchoredEnd:   anchoredEnd,
+		caseSensitive: caseSensitive,
+	}, nil
+}
+
+func (m *globMatcher) MatchString(str string) bool {
+	if !m.caseSensitive {
+		str = strings.ToLower(str)
+	}
+	s := []rune(str)
+
+	if len(m.segments) == 0 {
+		// Pattern was made up entirely of '*' (e.g. "*", "**"); it matches anything.
+		return true
+	}
+
+	if m.anchoredStart && m.anchoredEnd && len(m.segments) == 1 {
+		// No '*' in the pattern at all: it must match the whole string, not
+		// just a prefix of it.
+		seg := m.segments[0]
+		return len(s) == len(seg) && runesMatchAt(s, seg)
+	}
+
+	segs := m.segments
+	pos := 0
+
+	if m.anchoredStart {
+		if !runesHavePrefix(s, segs[0]) {
+			return false
+		}
+		pos = len(segs[0])
+		segs = segs[1:]
+	}
+
+	var last []rune
+	if m.anchoredEnd && len(segs) > 0 {
+		last = segs[len(segs)-1]
+		segs = segs[:len(segs)-1]
+	}
+
+	for _, seg := range segs {
+		idx := runesIndex(s[pos:], seg)
+		if idx < 0 {
+			return false
+		}
+		pos += idx + len(seg)
+	}
+
+	if m.anchoredEnd {
+		if pos > len(s)-len(last) {
+			return false
+		}
+		return runesHaveSuffix(s, last)
+	}
+
+	return true
+}
+
+// runesMatchAt reports whether seg matches s rune-for-rune, treating '?' in
+// seg as a wildcard for any single rune.
+func runesMatchAt(s, seg []rune) bool {
+	for i, r := range seg {
+		if r != '?' && r != s[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func runesHavePrefix(s, seg []rune) bool {
+	if len(seg) > len(s) {
+		return false
+	}
+	return runesMatchAt(s[:len(seg)], seg)
+}
+
+func runesHaveSuffix(s, seg []rune) bool {
+	if len(seg) > len(s) {
+		return false
+	}
+	return runesMatchAt(s[len(s)-len(seg):], seg)
+}
+
+// runesIndex finds the first index in s at which seg matches (honoring '?'
+// wildcards in seg), or -1 if seg does not occur in s.
+func runesIndex(s, seg []rune) int {
+	if len(seg) == 0 {
+		return 0
+	}
+	for i := 0; i+len(seg) <= len(s); i++ {
+		if runesMatchAt(s[i:i+len(seg)], seg) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,252 @@
+package dataframe
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func int64Series(name string, n int) *SeriesInt64 {
+	vals := make([]interface{}, n)
+	for i := 0; i < n; i++ {
+		vals[i] = int64(i)
+	}
+	return NewSeriesInt64(name, &SeriesInit{Size: n}, vals...)
+}
+
+// cancelingSeries wraps a *SeriesInt64 whose values equal their row index,
+// and cancels a context the first time a comparison involving cancelAt is
+// made. This lets tests deterministically cancel a Search/SearchBatch scan
+// partway through, even though those functions only expose bound
+// comparisons (not an arbitrary per-row hook like SearchFunc's fn).
+type cancelingSeries struct {
+	*SeriesInt64
+	cancelAt int64
+	cancel   context.CancelFunc
+	fired    bool
+}
+
+func (c *cancelingSeries) IsLessThanFunc(a, b interface{}) bool {
+	if !c.fired {
+		if v, ok := a.(int64); ok && v == c.cancelAt {
+			c.fired = true
+			c.cancel()
+		}
+	}
+	return c.SeriesInt64.IsLessThanFunc(a, b)
+}
+
+func TestGlobMatcherMatchString(t *testing.T) {
+	tests := []struct {
+		pattern string
+		input   string
+		want    bool
+	}{
+		{"abc", "abc", true},
+		{"abc", "abcdef", false},
+		{"abc", "ab", false},
+		{"a?c", "abc", true},
+		{"a?c", "abcXYZ", false},
+		{"a*c", "abXYZc", true},
+		{"a*c", "abXYZd", false},
+		{"*abc", "XYZabc", true},
+		{"abc*", "abcXYZ", true},
+		{"*abc*", "XYZabcXYZ", true},
+		{"*", "anything", true},
+	}
+
+	for _, tt := range tests {
+		m, err := compileGlob(tt.pattern, true)
+		if err != nil {
+			t.Fatalf("compileGlob(%q) returned error: %v", tt.pattern, err)
+		}
+		if got := m.MatchString(tt.input); got != tt.want {
+			t.Errorf("compileGlob(%q).MatchString(%q) = %v, want %v", tt.pattern, tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestCompileGlobEmptyPattern(t *testing.T) {
+	if _, err := compileGlob("", true); err == nil {
+		t.Error("compileGlob(\"\") expected an error, got nil")
+	}
+}
+
+func TestSearchBatchAlignment(t *testing.T) {
+	s := int64Series("test", 100)
+
+	queries := []SearchQuery{
+		{Lower: int64(10), Upper: int64(15)},  // 10,11,12,13,14,15
+		{Lower: int64(50), Upper: int64(50)},  // 50
+		{Lower: int64(90), Upper: int64(200)}, // 90..99
+	}
+
+	got, err := SearchBatch(context.Background(), s, queries)
+	if err != nil {
+		t.Fatalf("SearchBatch returned error: %v", err)
+	}
+
+	if len(got) != len(queries) {
+		t.Fatalf("len(got) = %d, want %d", len(got), len(queries))
+	}
+
+	want := [][]int{
+		{10, 11, 12, 13, 14, 15},
+		{50},
+		{90, 91, 92, 93, 94, 95, 96, 97, 98, 99},
+	}
+
+	for q := range want {
+		if !equalInts(got[q], want[q]) {
+			t.Errorf("query %d: got %v, want %v", q, got[q], want[q])
+		}
+	}
+}
+
+func TestSearchFuncPredicate(t *testing.T) {
+	s := int64Series("test", 20)
+
+	got, err := SearchFunc(context.Background(), s, func(val interface{}, row int) (bool, error) {
+		return val.(int64)%2 == 0, nil
+	})
+	if err != nil {
+		t.Fatalf("SearchFunc returned error: %v", err)
+	}
+
+	want := []int{0, 2, 4, 6, 8, 10, 12, 14, 16, 18}
+	if !equalInts(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSearchFuncPropagatesError(t *testing.T) {
+	s := int64Series("test", 20)
+
+	wantErr := errors.New("boom")
+
+	_, err := SearchFunc(context.Background(), s, func(val interface{}, row int) (bool, error) {
+		if val.(int64) == 5 {
+			return false, wantErr
+		}
+		return true, nil
+	})
+	if !errors.Is(err, wantErr) {
+		t.Errorf("SearchFunc error = %v, want %v", err, wantErr)
+	}
+}
+
+func TestSearchPatternGlob(t *testing.T) {
+	s := NewSeriesString("test", &SeriesInit{Size: 0}, []string{"apple", "banana", "apricot", "cherry"})
+
+	got, err := SearchPattern(context.Background(), s, "ap*", PatternOptions{Mode: PatternGlob})
+	if err != nil {
+		t.Fatalf("SearchPattern(glob) returned error: %v", err)
+	}
+
+	want := []int{0, 2} // apple, apricot
+	if !equalInts(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSearchPatternRegexp(t *testing.T) {
+	s := NewSeriesString("test", &SeriesInit{Size: 0}, []string{"apple", "banana", "apricot", "cherry"})
+
+	got, err := SearchPattern(context.Background(), s, "^(apple|cherry)$", PatternOptions{Mode: PatternRegexp})
+	if err != nil {
+		t.Fatalf("SearchPattern(regexp) returned error: %v", err)
+	}
+
+	want := []int{0, 3} // apple, cherry
+	if !equalInts(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSearchPatternNonSeriesString(t *testing.T) {
+	s := int64Series("test", 5)
+
+	if _, err := SearchPattern(context.Background(), s, "1", PatternOptions{}); err == nil {
+		t.Error("SearchPattern on a non-SeriesString expected an error, got nil")
+	}
+}
+
+// TestSearchCancellationKeepsPartialResults drives Search, SearchFunc and
+// SearchBatch with a single worker over a range matching every row, and
+// cancels the context partway through. Each should return the rows found
+// before cancellation instead of discarding the whole batch, per their
+// documented "found so far" contract.
+func TestSearchCancellationKeepsPartialResults(t *testing.T) {
+	const n = 2000
+	const cancelAt = 500
+
+	t.Run("Search", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		s := &cancelingSeries{SeriesInt64: int64Series("test", n), cancelAt: cancelAt, cancel: cancel}
+
+		got, err := Search(ctx, s, int64(0), int64(n), SearchOptions{NumWorkers: 1})
+
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("err = %v, want context.Canceled", err)
+		}
+		if len(got) == 0 {
+			t.Fatal("got 0 rows, want the rows found before cancellation")
+		}
+		if len(got) != cancelAt+1 {
+			t.Errorf("len(got) = %d, want %d", len(got), cancelAt+1)
+		}
+	})
+
+	t.Run("SearchFunc", func(t *testing.T) {
+		s := int64Series("test", n)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		got, err := SearchFunc(ctx, s, func(val interface{}, row int) (bool, error) {
+			if row == cancelAt {
+				cancel()
+			}
+			return true, nil
+		}, SearchOptions{NumWorkers: 1})
+
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("err = %v, want context.Canceled", err)
+		}
+		if len(got) != cancelAt+1 {
+			t.Errorf("len(got) = %d, want %d", len(got), cancelAt+1)
+		}
+	})
+
+	t.Run("SearchBatch", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		s := &cancelingSeries{SeriesInt64: int64Series("test", n), cancelAt: cancelAt, cancel: cancel}
+
+		queries := []SearchQuery{{Lower: int64(0), Upper: int64(n)}}
+
+		got, err := SearchBatch(ctx, s, queries, SearchOptions{NumWorkers: 1})
+
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("err = %v, want context.Canceled", err)
+		}
+		if len(got) != len(queries) {
+			t.Fatalf("len(got) = %d, want %d", len(got), len(queries))
+		}
+		if len(got[0]) == 0 {
+			t.Fatal("got 0 rows for the query, want the rows found before cancellation")
+		}
+		if len(got[0]) != cancelAt+1 {
+			t.Errorf("len(got[0]) = %d, want %d", len(got[0]), cancelAt+1)
+		}
+	})
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}